@@ -8,16 +8,29 @@ import (
 	"math"
 	"net/http"
 	"os"
+	"sort"
 	"strconv"
 	"time"
 
 	"cloud.google.com/go/bigquery"
+	awsconfig "github.com/aws/aws-sdk-go-v2/config"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
 	"github.com/gorilla/mux"
 	"google.golang.org/api/option"
 	"google.golang.org/api/sheets/v4"
 )
 
-// CostData represents billing data structure
+// Provider identifies which cloud a CostData record or CostSource originated from.
+type Provider string
+
+const (
+	ProviderGCP   Provider = "gcp"
+	ProviderAWS   Provider = "aws"
+	ProviderAzure Provider = "azure"
+)
+
+// CostData represents billing data structure, normalized across providers.
 type CostData struct {
 	ProjectID    string    `json:"project_id" bigquery:"project_id"`
 	ServiceName  string    `json:"service_name" bigquery:"service_description"`
@@ -27,6 +40,7 @@ type CostData struct {
 	Credits      float64   `json:"credits" bigquery:"credits"`
 	Currency     string    `json:"currency" bigquery:"currency"`
 	Location     string    `json:"location" bigquery:"location_location"`
+	Provider     Provider  `json:"provider" bigquery:"-"`
 }
 
 // CostTrend represents cost trend analysis
@@ -37,6 +51,17 @@ type CostTrend struct {
 	ProjectCost map[string]float64 `json:"project_cost"`
 }
 
+// ForecastPoint is a single forecasted day, with a prediction interval
+// derived from the Holt-Winters fit's residual stddev so clients can render
+// band charts.
+type ForecastPoint struct {
+	Date        time.Time          `json:"date"`
+	TotalCost   float64            `json:"total_cost"`
+	ServiceCost map[string]float64 `json:"service_cost"`
+	LowerBound  float64            `json:"lower_bound"`
+	UpperBound  float64            `json:"upper_bound"`
+}
+
 // Anomaly represents detected cost anomalies
 type Anomaly struct {
 	Date           time.Time `json:"date"`
@@ -49,220 +74,288 @@ type Anomaly struct {
 	Description    string    `json:"description"`
 }
 
+// serviceVersion is reported via the cloudcost_build_info metric.
+const serviceVersion = "dev"
+
+// GCPConfig holds BigQuery billing-export connection details.
+type GCPConfig struct {
+	ProjectID string
+	DatasetID string
+	TableID   string
+}
+
+// AWSConfig holds the S3 location of a Cost & Usage Report export.
+type AWSConfig struct {
+	Region string
+	Bucket string
+	Prefix string
+}
+
+// AzureConfig holds the Blob Storage location of a Cost Management Export.
+type AzureConfig struct {
+	StorageAccountURL string
+	ContainerName     string
+	Prefix            string
+}
+
+// NotifierConfig holds credentials for the alerting destinations a
+// NotificationRule can route anomalies to. Any left blank are not registered.
+type NotifierConfig struct {
+	PagerDutyRoutingKey string
+	SlackWebhookURL     string
+	WebhookURL          string
+	WebhookSecret       string
+}
+
+// Config aggregates the per-provider settings CostAnalytics needs to build
+// its CostSource adapters, plus the currency all costs are normalized to.
+// A nil provider config means that cloud is not ingested.
+type Config struct {
+	ReportingCurrency string
+	ExchangeRates     map[string]float64 // units of ReportingCurrency per 1 unit of source currency
+
+	GCP   *GCPConfig
+	AWS   *AWSConfig
+	Azure *AzureConfig
+
+	Notifiers NotifierConfig
+}
+
 // CostAnalytics main service struct
 type CostAnalytics struct {
-	bqClient     *bigquery.Client
-	sheetsClient *sheets.Service
-	projectID    string
-	datasetID    string
-	tableID      string
+	bqClient          *bigquery.Client
+	sheetsClient      *sheets.Service
+	sources           []CostSource
+	reportingCurrency string
+	cache             *resultCache
+	notificationRules *notificationRuleStore
+	metrics           *metricsCollector
 }
 
-// NewCostAnalytics creates a new analytics service
-func NewCostAnalytics(projectID, datasetID, tableID string) (*CostAnalytics, error) {
+// NewCostAnalytics creates a new analytics service, wiring up a CostSource
+// for every configured cloud provider.
+func NewCostAnalytics(cfg Config) (*CostAnalytics, error) {
 	ctx := context.Background()
-	
-	// Initialize BigQuery client
-	bqClient, err := bigquery.NewClient(ctx, projectID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create BigQuery client: %v", err)
+
+	reportingCurrency := cfg.ReportingCurrency
+	if reportingCurrency == "" {
+		reportingCurrency = "USD"
+	}
+	converter := newCurrencyConverter(reportingCurrency, cfg.ExchangeRates)
+
+	ca := &CostAnalytics{
+		reportingCurrency: reportingCurrency,
+		cache:             newResultCache(),
+		notificationRules: newNotificationRuleStore(),
+		metrics:           newMetricsCollector(serviceVersion),
 	}
 
-	// Initialize Google Sheets client
+	// Initialize Google Sheets client (always needed for dashboard export).
 	sheetsClient, err := sheets.NewService(ctx, option.WithScopes(sheets.SpreadsheetsScope))
 	if err != nil {
 		return nil, fmt.Errorf("failed to create Sheets client: %v", err)
 	}
+	ca.sheetsClient = sheetsClient
 
-	return &CostAnalytics{
-		bqClient:     bqClient,
-		sheetsClient: sheetsClient,
-		projectID:    projectID,
-		datasetID:    datasetID,
-		tableID:      tableID,
-	}, nil
-}
+	if cfg.GCP != nil {
+		bqClient, err := bigquery.NewClient(ctx, cfg.GCP.ProjectID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create BigQuery client: %v", err)
+		}
+		ca.bqClient = bqClient
+		gcpSource := NewGCPCostSource(bqClient, cfg.GCP.ProjectID, cfg.GCP.DatasetID, cfg.GCP.TableID, converter)
+		gcpSource.onBytesScanned = func(bytes int64) { ca.metrics.bytesScanned.Add(float64(bytes)) }
+		ca.sources = append(ca.sources, gcpSource)
+	}
 
-// GetCostTrends analyzes cost trends over time
-func (ca *CostAnalytics) GetCostTrends(days int) ([]CostTrend, error) {
-	ctx := context.Background()
-	
-	query := fmt.Sprintf(`
-		WITH daily_costs AS (
-			SELECT 
-				DATE(usage_start_time) as usage_date,
-				project.id as project_id,
-				service.description as service_name,
-				SUM(cost) as total_cost,
-				SUM(IFNULL((SELECT SUM(amount) FROM UNNEST(credits)), 0)) as total_credits
-			FROM %s.%s.%s
-			WHERE DATE(usage_start_time) >= DATE_SUB(CURRENT_DATE(), INTERVAL %d DAY)
-			GROUP BY usage_date, project_id, service_name
-		),
-		aggregated_costs AS (
-			SELECT 
-				usage_date,
-				SUM(total_cost + total_credits) as daily_total,
-				ARRAY_AGG(STRUCT(service_name, total_cost + total_credits)) as service_costs,
-				ARRAY_AGG(STRUCT(project_id, total_cost + total_credits)) as project_costs
-			FROM daily_costs
-			GROUP BY usage_date
-			ORDER BY usage_date
-		)
-		SELECT * FROM aggregated_costs
-	`, ca.projectID, ca.datasetID, ca.tableID, days)
-
-	q := ca.bqClient.Query(query)
-	it, err := q.Read(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to execute query: %v", err)
-	}
-
-	var trends []CostTrend
-	for {
-		var row struct {
-			UsageDate    time.Time `bigquery:"usage_date"`
-			DailyTotal   float64   `bigquery:"daily_total"`
-			ServiceCosts []struct {
-				ServiceName string  `bigquery:"service_name"`
-				Cost        float64 `bigquery:"f1_"`
-			} `bigquery:"service_costs"`
-			ProjectCosts []struct {
-				ProjectID string  `bigquery:"project_id"`
-				Cost      float64 `bigquery:"f1_"`
-			} `bigquery:"project_costs"`
+	if cfg.AWS != nil {
+		awsCfg, err := awsconfig.LoadDefaultConfig(ctx, awsconfig.WithRegion(cfg.AWS.Region))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load AWS config: %v", err)
 		}
+		ca.sources = append(ca.sources, NewAWSCostSource(awsCfg, cfg.AWS.Bucket, cfg.AWS.Prefix, converter))
+	}
 
-		err := it.Next(&row)
+	if cfg.Azure != nil {
+		cred, err := azidentity.NewDefaultAzureCredential(nil)
 		if err != nil {
-			break
+			return nil, fmt.Errorf("failed to create Azure credential: %v", err)
 		}
+		blobClient, err := azblob.NewClient(cfg.Azure.StorageAccountURL, cred, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Azure Blob client: %v", err)
+		}
+		ca.sources = append(ca.sources, NewAzureCostSource(blobClient, cfg.Azure.ContainerName, cfg.Azure.Prefix, converter))
+	}
 
-		serviceCosts := make(map[string]float64)
-		for _, sc := range row.ServiceCosts {
-			serviceCosts[sc.ServiceName] = sc.Cost
+	if len(ca.sources) == 0 {
+		return nil, fmt.Errorf("at least one cloud provider (GCP, AWS, or Azure) must be configured")
+	}
+
+	if cfg.Notifiers.PagerDutyRoutingKey != "" {
+		ca.notificationRules.registerNotifier("pagerduty", NewPagerDutyNotifier(cfg.Notifiers.PagerDutyRoutingKey))
+	}
+	if cfg.Notifiers.SlackWebhookURL != "" {
+		ca.notificationRules.registerNotifier("slack", NewSlackNotifier(cfg.Notifiers.SlackWebhookURL))
+	}
+	if cfg.Notifiers.WebhookURL != "" {
+		ca.notificationRules.registerNotifier("webhook", NewWebhookNotifier(cfg.Notifiers.WebhookURL, cfg.Notifiers.WebhookSecret))
+	}
+
+	return ca, nil
+}
+
+// fetchMerged pulls normalized cost records from every configured
+// CostSource and concatenates them into a single stream for the window.
+func (ca *CostAnalytics) fetchMerged(ctx context.Context, window CostWindow) ([]CostData, error) {
+	var merged []CostData
+	for _, source := range ca.sources {
+		records, err := source.Fetch(ctx, window)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", source.Name(), err)
 		}
+		merged = append(merged, records...)
+	}
+	return merged, nil
+}
 
-		projectCosts := make(map[string]float64)
-		for _, pc := range row.ProjectCosts {
-			projectCosts[pc.ProjectID] = pc.Cost
+// dailyKey groups merged cost records by calendar day, project, and service.
+type dailyKey struct {
+	date    time.Time
+	project string
+	service string
+}
+
+// aggregateDaily sums Cost+Credits per (date, project, service) across the
+// merged multi-cloud record stream.
+func aggregateDaily(records []CostData) map[dailyKey]float64 {
+	totals := make(map[dailyKey]float64)
+	for _, r := range records {
+		key := dailyKey{
+			date:    r.UsageDate.Truncate(24 * time.Hour),
+			project: r.ProjectID,
+			service: r.ServiceName,
 		}
+		totals[key] += r.Cost + r.Credits
+	}
+	return totals
+}
 
-		trends = append(trends, CostTrend{
-			Date:        row.UsageDate,
-			TotalCost:   row.DailyTotal,
-			ServiceCost: serviceCosts,
-			ProjectCost: projectCosts,
-		})
+// GetCostTrends analyzes cost trends over time across every configured cloud.
+func (ca *CostAnalytics) GetCostTrends(days int) ([]CostTrend, error) {
+	ctx := context.Background()
+
+	now := time.Now().UTC().Truncate(24 * time.Hour)
+	window := CostWindow{Start: now.AddDate(0, 0, -days), End: now}
+
+	records, err := ca.fetchMerged(ctx, window)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch cost data: %v", err)
 	}
 
+	byDay := aggregateDaily(records)
+
+	trendsByDate := make(map[time.Time]*CostTrend)
+	for key, cost := range byDay {
+		trend, ok := trendsByDate[key.date]
+		if !ok {
+			trend = &CostTrend{
+				Date:        key.date,
+				ServiceCost: make(map[string]float64),
+				ProjectCost: make(map[string]float64),
+			}
+			trendsByDate[key.date] = trend
+		}
+		trend.TotalCost += cost
+		trend.ServiceCost[key.service] += cost
+		trend.ProjectCost[key.project] += cost
+	}
+
+	trends := make([]CostTrend, 0, len(trendsByDate))
+	for _, trend := range trendsByDate {
+		trends = append(trends, *trend)
+	}
+	sort.Slice(trends, func(i, j int) bool { return trends[i].Date.Before(trends[j].Date) })
+
 	return trends, nil
 }
 
-// DetectAnomalies uses statistical analysis to detect cost anomalies
-func (ca *CostAnalytics) DetectAnomalies(days int, threshold float64) ([]Anomaly, error) {
+// seriesPredictionsForWindow fetches and aggregates cost data for the last
+// `days`, then fits the decaying-histogram percentile predictor to every
+// (project, service) series.
+func (ca *CostAnalytics) seriesPredictionsForWindow(days int, margin float64) (map[string]*seriesPrediction, error) {
 	ctx := context.Background()
-	
-	query := fmt.Sprintf(`
-		WITH historical_data AS (
-			SELECT 
-				DATE(usage_start_time) as usage_date,
-				project.id as project_id,
-				service.description as service_name,
-				SUM(cost + IFNULL((SELECT SUM(amount) FROM UNNEST(credits)), 0)) as daily_cost
-			FROM %s.%s.%s
-			WHERE DATE(usage_start_time) >= DATE_SUB(CURRENT_DATE(), INTERVAL %d DAY)
-			GROUP BY usage_date, project_id, service_name
-		),
-		stats AS (
-			SELECT 
-				project_id,
-				service_name,
-				AVG(daily_cost) as avg_cost,
-				STDDEV(daily_cost) as stddev_cost,
-				COUNT(*) as data_points
-			FROM historical_data
-			WHERE usage_date < DATE_SUB(CURRENT_DATE(), INTERVAL 1 DAY)
-			GROUP BY project_id, service_name
-			HAVING COUNT(*) >= 7
-		),
-		recent_costs AS (
-			SELECT 
-				DATE(usage_start_time) as usage_date,
-				project.id as project_id,
-				service.description as service_name,
-				SUM(cost + IFNULL((SELECT SUM(amount) FROM UNNEST(credits)), 0)) as daily_cost
-			FROM %s.%s.%s
-			WHERE DATE(usage_start_time) = DATE_SUB(CURRENT_DATE(), INTERVAL 1 DAY)
-			GROUP BY usage_date, project_id, service_name
-		)
-		SELECT 
-			r.usage_date,
-			r.project_id,
-			r.service_name,
-			r.daily_cost as actual_cost,
-			s.avg_cost as expected_cost,
-			s.stddev_cost,
-			ABS(r.daily_cost - s.avg_cost) / NULLIF(s.stddev_cost, 0) as z_score
-		FROM recent_costs r
-		JOIN stats s ON r.project_id = s.project_id AND r.service_name = s.service_name
-		WHERE ABS(r.daily_cost - s.avg_cost) / NULLIF(s.stddev_cost, 0) > %f
-		ORDER BY z_score DESC
-	`, ca.projectID, ca.datasetID, ca.tableID, days, ca.projectID, ca.datasetID, ca.tableID, threshold)
-
-	q := ca.bqClient.Query(query)
-	it, err := q.Read(ctx)
+
+	now := time.Now().UTC().Truncate(24 * time.Hour)
+	window := CostWindow{Start: now.AddDate(0, 0, -days), End: now}
+
+	records, err := ca.fetchMerged(ctx, window)
 	if err != nil {
-		return nil, fmt.Errorf("failed to execute anomaly query: %v", err)
+		return nil, fmt.Errorf("failed to fetch cost data: %v", err)
+	}
+
+	byDay := aggregateDaily(records)
+	cfg := defaultAnomalyDetectorConfig
+	cfg.Margin = margin
+
+	return buildSeriesPredictions(byDay, cfg, now), nil
+}
+
+// DetectAnomalies flags the most recent day's cost for each (project,
+// service) series as anomalous when it exceeds the series' predicted P95
+// (decaying-histogram percentile, see anomaly_detector.go) by `margin`.
+func (ca *CostAnalytics) DetectAnomalies(days int, margin float64) ([]Anomaly, error) {
+	predictions, err := ca.seriesPredictionsForWindow(days, margin)
+	if err != nil {
+		return nil, err
 	}
 
 	var anomalies []Anomaly
-	for {
-		var row struct {
-			UsageDate    time.Time `bigquery:"usage_date"`
-			ProjectID    string    `bigquery:"project_id"`
-			ServiceName  string    `bigquery:"service_name"`
-			ActualCost   float64   `bigquery:"actual_cost"`
-			ExpectedCost float64   `bigquery:"expected_cost"`
-			StddevCost   float64   `bigquery:"stddev_cost"`
-			ZScore       float64   `bigquery:"z_score"`
+	for _, pred := range predictions {
+		if len(pred.history) < 8 || pred.predicted <= 0 {
+			continue
 		}
 
-		err := it.Next(&row)
-		if err != nil {
-			break
+		recent := pred.history[len(pred.history)-1]
+		if recent.cost <= pred.predicted {
+			continue
 		}
 
-		deviationPct := ((row.ActualCost - row.ExpectedCost) / row.ExpectedCost) * 100
-		severity := "Medium"
-		if row.ZScore > 3 {
+		deviationPct := ((recent.cost - pred.predicted) / pred.predicted) * 100
+		severity := "Low"
+		switch {
+		case deviationPct > 100:
 			severity = "High"
-		} else if row.ZScore > 2 {
+		case deviationPct > 50:
 			severity = "Medium"
-		} else {
-			severity = "Low"
 		}
 
-		description := fmt.Sprintf("Cost spike detected for %s in project %s. Expected: $%.2f, Actual: $%.2f",
-			row.ServiceName, row.ProjectID, row.ExpectedCost, row.ActualCost)
+		description := fmt.Sprintf("Cost spike detected for %s in project %s. Expected (P%.0f): $%.2f, Actual: $%.2f",
+			pred.serviceName, pred.projectID, defaultAnomalyDetectorConfig.Percentile*100, pred.predicted, recent.cost)
 
 		anomalies = append(anomalies, Anomaly{
-			Date:           row.UsageDate,
-			ProjectID:      row.ProjectID,
-			ServiceName:    row.ServiceName,
-			ActualCost:     row.ActualCost,
-			ExpectedCost:   row.ExpectedCost,
-			DeviationPct:   deviationPct,
-			Severity:       severity,
-			Description:    description,
+			Date:         recent.date,
+			ProjectID:    pred.projectID,
+			ServiceName:  pred.serviceName,
+			ActualCost:   recent.cost,
+			ExpectedCost: pred.predicted,
+			DeviationPct: deviationPct,
+			Severity:     severity,
+			Description:  description,
 		})
 	}
 
+	sort.Slice(anomalies, func(i, j int) bool {
+		return anomalies[i].DeviationPct > anomalies[j].DeviationPct
+	})
+
 	return anomalies, nil
 }
 
 // UpdateGoogleSheets updates the dashboard in Google Sheets
 func (ca *CostAnalytics) UpdateGoogleSheets(spreadsheetID string, trends []CostTrend, anomalies []Anomaly) error {
+	ctx := context.Background()
+
 	// Prepare trend data for sheets
 	var trendValues [][]interface{}
 	trendValues = append(trendValues, []interface{}{"Date", "Total Cost", "Top Service", "Top Service Cost"})
@@ -291,8 +384,11 @@ func (ca *CostAnalytics) UpdateGoogleSheets(spreadsheetID string, trends []CostT
 		Values: trendValues,
 	}
 
-	_, err := ca.sheetsClient.Spreadsheets.Values.Update(spreadsheetID, trendsRange, trendsVR).
-		ValueInputOption("RAW").Do()
+	err := retryWithBackoff(ctx, defaultRetryConfig, func() error {
+		_, err := ca.sheetsClient.Spreadsheets.Values.Update(spreadsheetID, trendsRange, trendsVR).
+			ValueInputOption("RAW").Do()
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to update trends sheet: %v", err)
 	}
@@ -322,8 +418,11 @@ func (ca *CostAnalytics) UpdateGoogleSheets(spreadsheetID string, trends []CostT
 		Values: anomalyValues,
 	}
 
-	_, err = ca.sheetsClient.Spreadsheets.Values.Update(spreadsheetID, anomaliesRange, anomaliesVR).
-		ValueInputOption("RAW").Do()
+	err = retryWithBackoff(ctx, defaultRetryConfig, func() error {
+		_, err := ca.sheetsClient.Spreadsheets.Values.Update(spreadsheetID, anomaliesRange, anomaliesVR).
+			ValueInputOption("RAW").Do()
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("failed to update anomalies sheet: %v", err)
 	}
@@ -331,50 +430,85 @@ func (ca *CostAnalytics) UpdateGoogleSheets(spreadsheetID string, trends []CostT
 	return nil
 }
 
-// ForecastCosts predicts future costs using simple linear regression
-func (ca *CostAnalytics) ForecastCosts(days int) ([]CostTrend, error) {
-	trends, err := ca.GetCostTrends(30) // Use 30 days of historical data
+// historyDaysForForecast is how much history ForecastCosts fetches: 8 weeks
+// gives the Holt-Winters fitter several full seasonal cycles to work with.
+const historyDaysForForecast = 56
+
+// denseDailySeries turns GetCostTrends' (possibly sparse) output into
+// zero-filled daily series suitable for Holt-Winters, which needs a fixed
+// period length to track weekly seasonality.
+func denseDailySeries(trends []CostTrend) (dates []time.Time, total []float64, byService map[string][]float64) {
+	start := trends[0].Date
+	end := trends[len(trends)-1].Date
+
+	totalByDate := make(map[time.Time]float64, len(trends))
+	serviceByDate := make(map[time.Time]map[string]float64, len(trends))
+	services := make(map[string]bool)
+	for _, t := range trends {
+		totalByDate[t.Date] = t.TotalCost
+		serviceByDate[t.Date] = t.ServiceCost
+		for service := range t.ServiceCost {
+			services[service] = true
+		}
+	}
+
+	byService = make(map[string][]float64, len(services))
+	for d := start; !d.After(end); d = d.AddDate(0, 0, 1) {
+		dates = append(dates, d)
+		total = append(total, totalByDate[d])
+		for service := range services {
+			byService[service] = append(byService[service], serviceByDate[d][service])
+		}
+	}
+
+	return dates, total, byService
+}
+
+// ForecastCosts predicts future costs using Holt-Winters triple exponential
+// smoothing with weekly seasonality (see holt_winters.go), fitting each
+// service's series independently and summing per-service forecasts into the
+// aggregate, since services can have very different trend/seasonal shapes.
+func (ca *CostAnalytics) ForecastCosts(days int) ([]ForecastPoint, error) {
+	trends, err := ca.GetCostTrends(historyDaysForForecast)
 	if err != nil {
 		return nil, err
 	}
 
-	if len(trends) < 7 {
-		return nil, fmt.Errorf("insufficient data for forecasting")
+	if len(trends) < 2*seasonLength {
+		return nil, fmt.Errorf("insufficient data for forecasting: need at least %d days of history, have %d", 2*seasonLength, len(trends))
 	}
 
-	// Simple linear regression for forecasting
-	var sumX, sumY, sumXY, sumX2 float64
-	n := float64(len(trends))
+	dates, _, serviceSeries := denseDailySeries(trends)
 
-	for i, trend := range trends {
-		x := float64(i)
-		y := trend.TotalCost
-		sumX += x
-		sumY += y
-		sumXY += x * y
-		sumX2 += x * x
+	holdout := seasonLength
+	if max := len(dates) / 3; holdout > max {
+		holdout = max
 	}
 
-	// Calculate slope and intercept
-	slope := (n*sumXY - sumX*sumY) / (n*sumX2 - sumX*sumX)
-	intercept := (sumY - slope*sumX) / n
-
-	// Generate forecasts
-	var forecasts []CostTrend
-	lastDate := trends[len(trends)-1].Date
+	serviceFits := make(map[string]holtWintersFit, len(serviceSeries))
+	for service, series := range serviceSeries {
+		serviceFits[service] = gridSearchHoltWinters(series, holdout)
+	}
 
-	for i := 1; i <= days; i++ {
-		x := float64(len(trends) + i - 1)
-		predictedCost := slope*x + intercept
-		
-		// Ensure positive prediction
-		if predictedCost < 0 {
-			predictedCost = trends[len(trends)-1].TotalCost * 0.9
+	lastDate := dates[len(dates)-1]
+	forecasts := make([]ForecastPoint, 0, days)
+	for h := 1; h <= days; h++ {
+		serviceCost := make(map[string]float64, len(serviceFits))
+		var total, intervalSumSq float64
+		for service, fit := range serviceFits {
+			predicted := math.Max(0, fit.forecast(h))
+			serviceCost[service] = predicted
+			total += predicted
+			interval := fit.predictionInterval(h)
+			intervalSumSq += interval * interval
 		}
 
-		forecasts = append(forecasts, CostTrend{
-			Date:      lastDate.AddDate(0, 0, i),
-			TotalCost: predictedCost,
+		forecasts = append(forecasts, ForecastPoint{
+			Date:        lastDate.AddDate(0, 0, h),
+			TotalCost:   total,
+			ServiceCost: serviceCost,
+			LowerBound:  math.Max(0, total-math.Sqrt(intervalSumSq)),
+			UpperBound:  total + math.Sqrt(intervalSumSq),
 		})
 	}
 
@@ -391,33 +525,111 @@ func (ca *CostAnalytics) handleGetTrends(w http.ResponseWriter, r *http.Request)
 		}
 	}
 
+	key := cacheKey("trends", days)
+	if days == defaultCachedDays && r.URL.Query().Get("refresh") != "1" {
+		if entry, ok := ca.cache.get(key); ok {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(entry.value)
+			return
+		}
+	}
+
 	trends, err := ca.GetCostTrends(days)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	result := cachedTrends{Trends: trends, ComputedAt: time.Now()}
+	if days == defaultCachedDays {
+		ca.cache.set(key, result)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(trends)
+	json.NewEncoder(w).Encode(result)
 }
 
 func (ca *CostAnalytics) handleGetAnomalies(w http.ResponseWriter, r *http.Request) {
-	thresholdStr := r.URL.Query().Get("threshold")
-	threshold := 2.0
-	if thresholdStr != "" {
-		if t, err := strconv.ParseFloat(thresholdStr, 64); err == nil {
-			threshold = t
+	marginStr := r.URL.Query().Get("threshold")
+	margin := defaultCachedThreshold
+	if marginStr != "" {
+		if m, err := strconv.ParseFloat(marginStr, 64); err == nil {
+			margin = m
+		}
+	}
+
+	key := cacheKey("anomalies", defaultCachedDays, margin)
+	if margin == defaultCachedThreshold && r.URL.Query().Get("refresh") != "1" {
+		if entry, ok := ca.cache.get(key); ok {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(entry.value)
+			return
 		}
 	}
 
-	anomalies, err := ca.DetectAnomalies(30, threshold)
+	anomalies, err := ca.DetectAnomalies(defaultCachedDays, margin)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	result := cachedAnomalies{Anomalies: anomalies, ComputedAt: time.Now()}
+	if margin == defaultCachedThreshold {
+		ca.cache.set(key, result)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(anomalies)
+	json.NewEncoder(w).Encode(result)
+}
+
+// AnomalySeriesDebug exposes one series' historical costs, the predicted P95,
+// and the predictor's MAE so operators can tune lambda/percentile.
+type AnomalySeriesDebug struct {
+	ProjectID   string      `json:"project_id"`
+	ServiceName string      `json:"service_name"`
+	Historical  []CostPoint `json:"historical"`
+	Predicted   float64     `json:"predicted_p95"`
+	MAE         float64     `json:"mae"`
+}
+
+// CostPoint is a single (date, cost) observation in an AnomalySeriesDebug series.
+type CostPoint struct {
+	Date time.Time `json:"date"`
+	Cost float64   `json:"cost"`
+}
+
+func (ca *CostAnalytics) handleGetAnomaliesDebug(w http.ResponseWriter, r *http.Request) {
+	marginStr := r.URL.Query().Get("threshold")
+	margin := defaultCachedThreshold
+	if marginStr != "" {
+		if m, err := strconv.ParseFloat(marginStr, 64); err == nil {
+			margin = m
+		}
+	}
+
+	predictions, err := ca.seriesPredictionsForWindow(defaultCachedDays, margin)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	debug := make([]AnomalySeriesDebug, 0, len(predictions))
+	for _, pred := range predictions {
+		historical := make([]CostPoint, 0, len(pred.history))
+		for _, p := range pred.history {
+			historical = append(historical, CostPoint{Date: p.date, Cost: p.cost})
+		}
+		debug = append(debug, AnomalySeriesDebug{
+			ProjectID:   pred.projectID,
+			ServiceName: pred.serviceName,
+			Historical:  historical,
+			Predicted:   pred.predicted,
+			MAE:         pred.mae,
+		})
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(debug)
 }
 
 func (ca *CostAnalytics) handleGetForecast(w http.ResponseWriter, r *http.Request) {
@@ -429,14 +641,28 @@ func (ca *CostAnalytics) handleGetForecast(w http.ResponseWriter, r *http.Reques
 		}
 	}
 
+	key := cacheKey("forecast", days)
+	if days == defaultCachedForecastDays && r.URL.Query().Get("refresh") != "1" {
+		if entry, ok := ca.cache.get(key); ok {
+			w.Header().Set("Content-Type", "application/json")
+			json.NewEncoder(w).Encode(entry.value)
+			return
+		}
+	}
+
 	forecast, err := ca.ForecastCosts(days)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
+	result := cachedForecast{Forecast: forecast, ComputedAt: time.Now()}
+	if days == defaultCachedForecastDays {
+		ca.cache.set(key, result)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
-	json.NewEncoder(w).Encode(forecast)
+	json.NewEncoder(w).Encode(result)
 }
 
 func (ca *CostAnalytics) handleUpdateSheets(w http.ResponseWriter, r *http.Request) {
@@ -468,31 +694,88 @@ func (ca *CostAnalytics) handleUpdateSheets(w http.ResponseWriter, r *http.Reque
 	json.NewEncoder(w).Encode(map[string]string{"status": "updated"})
 }
 
-func main() {
+// configFromEnv builds a Config from environment variables. GCP is the
+// original source and stays required; AWS and Azure are opt-in so existing
+// single-cloud deployments keep working unchanged.
+func configFromEnv() Config {
+	cfg := Config{ReportingCurrency: os.Getenv("REPORTING_CURRENCY")}
+
 	projectID := os.Getenv("GCP_PROJECT_ID")
 	datasetID := os.Getenv("BQ_DATASET_ID")
 	tableID := os.Getenv("BQ_TABLE_ID")
-	port := os.Getenv("PORT")
-
 	if projectID == "" || datasetID == "" || tableID == "" {
 		log.Fatal("Environment variables GCP_PROJECT_ID, BQ_DATASET_ID, and BQ_TABLE_ID are required")
 	}
+	cfg.GCP = &GCPConfig{ProjectID: projectID, DatasetID: datasetID, TableID: tableID}
 
+	if bucket := os.Getenv("AWS_CUR_BUCKET"); bucket != "" {
+		cfg.AWS = &AWSConfig{
+			Region: os.Getenv("AWS_REGION"),
+			Bucket: bucket,
+			Prefix: os.Getenv("AWS_CUR_PREFIX"),
+		}
+	}
+
+	if container := os.Getenv("AZURE_EXPORT_CONTAINER"); container != "" {
+		cfg.Azure = &AzureConfig{
+			StorageAccountURL: os.Getenv("AZURE_STORAGE_ACCOUNT_URL"),
+			ContainerName:     container,
+			Prefix:            os.Getenv("AZURE_EXPORT_PREFIX"),
+		}
+	}
+
+	cfg.Notifiers = NotifierConfig{
+		PagerDutyRoutingKey: os.Getenv("PAGERDUTY_ROUTING_KEY"),
+		SlackWebhookURL:     os.Getenv("SLACK_WEBHOOK_URL"),
+		WebhookURL:          os.Getenv("ALERT_WEBHOOK_URL"),
+		WebhookSecret:       os.Getenv("ALERT_WEBHOOK_SECRET"),
+	}
+
+	return cfg
+}
+
+func main() {
+	port := os.Getenv("PORT")
 	if port == "" {
 		port = "8080"
 	}
 
-	analytics, err := NewCostAnalytics(projectID, datasetID, tableID)
+	analytics, err := NewCostAnalytics(configFromEnv())
 	if err != nil {
 		log.Fatalf("Failed to initialize analytics service: %v", err)
 	}
-	defer analytics.bqClient.Close()
+	if analytics.bqClient != nil {
+		defer analytics.bqClient.Close()
+	}
+
+	refreshInterval := defaultCacheRefreshInterval
+	if intervalStr := os.Getenv("CACHE_REFRESH_INTERVAL"); intervalStr != "" {
+		if parsed, err := time.ParseDuration(intervalStr); err == nil {
+			refreshInterval = parsed
+		}
+	}
+	analytics.startCacheRefreshLoop(refreshInterval)
+
+	alertInterval := defaultAlertCheckInterval
+	if intervalStr := os.Getenv("ALERT_CHECK_INTERVAL"); intervalStr != "" {
+		if parsed, err := time.ParseDuration(intervalStr); err == nil {
+			alertInterval = parsed
+		}
+	}
+	analytics.startAnomalyAlertLoop(alertInterval)
+	analytics.startMetricsRefreshLoop(refreshInterval)
 
 	r := mux.NewRouter()
+	r.Handle("/metrics", metricsHandler()).Methods("GET")
 	r.HandleFunc("/api/trends", analytics.handleGetTrends).Methods("GET")
 	r.HandleFunc("/api/anomalies", analytics.handleGetAnomalies).Methods("GET")
+	r.HandleFunc("/api/anomalies/debug", analytics.handleGetAnomaliesDebug).Methods("GET")
 	r.HandleFunc("/api/forecast", analytics.handleGetForecast).Methods("GET")
 	r.HandleFunc("/api/update-sheets", analytics.handleUpdateSheets).Methods("POST")
+	r.HandleFunc("/api/notification-rules", analytics.handleListNotificationRules).Methods("GET")
+	r.HandleFunc("/api/notification-rules", analytics.handleCreateNotificationRule).Methods("POST")
+	r.HandleFunc("/api/notification-rules/{id}", analytics.handleUpdateNotificationRule).Methods("PUT")
+	r.HandleFunc("/api/notification-rules/{id}", analytics.handleDeleteNotificationRule).Methods("DELETE")
 
 	// Health check endpoint
 	r.HandleFunc("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -0,0 +1,164 @@
+package main
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// anomalyDetectorConfig tunes the percentile predictor used by DetectAnomalies.
+type anomalyDetectorConfig struct {
+	// Lambda controls how fast older samples lose weight: weight = exp(-Lambda * ageInDays).
+	Lambda float64
+	// Percentile is the target percentile (e.g. 0.95 for P95) a day's cost must
+	// exceed, scaled by Margin, to be flagged anomalous.
+	Percentile float64
+	// Margin is the multiplier applied to the predicted percentile before
+	// comparing against the actual cost (e.g. 1.0 means "any excess over P95").
+	Margin float64
+	// Buckets is the number of fixed-width histogram buckets spanning the
+	// observed cost range for a series.
+	Buckets int
+}
+
+// defaultAnomalyDetectorConfig matches the previous z-score guardrails:
+// P95 with no margin inflation and a reasonably fast decay.
+var defaultAnomalyDetectorConfig = anomalyDetectorConfig{
+	Lambda:     0.05,
+	Percentile: 0.95,
+	Margin:     1.0,
+	Buckets:    20,
+}
+
+// seriesPoint is one daily cost observation for a (project, service) series.
+type seriesPoint struct {
+	date time.Time
+	cost float64
+}
+
+// seriesPrediction is the percentile predictor's output for one series,
+// including the MAE of the predictor over the historical window so callers
+// can judge whether lambda/percentile need tuning.
+type seriesPrediction struct {
+	projectID   string
+	serviceName string
+	predicted   float64
+	history     []seriesPoint
+	mae         float64
+}
+
+// decayingHistogram is a fixed-width histogram over a series' observed cost
+// range where each sample's contribution decays with age, per
+// weight = exp(-lambda * ageInDays). It supports reading back an
+// approximate percentile by scanning bucket weights.
+type decayingHistogram struct {
+	min, max   float64
+	width      float64
+	buckets    []float64
+	total      float64
+	numBuckets int
+}
+
+func newDecayingHistogram(min, max float64, numBuckets int) *decayingHistogram {
+	if numBuckets < 1 {
+		numBuckets = 1
+	}
+	if max <= min {
+		max = min + 1
+	}
+	return &decayingHistogram{
+		min:        min,
+		max:        max,
+		width:      (max - min) / float64(numBuckets),
+		buckets:    make([]float64, numBuckets),
+		numBuckets: numBuckets,
+	}
+}
+
+func (h *decayingHistogram) add(value, weight float64) {
+	idx := int((value - h.min) / h.width)
+	if idx < 0 {
+		idx = 0
+	}
+	if idx >= h.numBuckets {
+		idx = h.numBuckets - 1
+	}
+	h.buckets[idx] += weight
+	h.total += weight
+}
+
+// percentile scans buckets from the lowest value upward until the cumulative
+// weight exceeds `p` (in [0,1]) of the total weight, then returns that
+// bucket's upper edge as the estimate.
+func (h *decayingHistogram) percentile(p float64) float64 {
+	if h.total == 0 {
+		return h.max
+	}
+	target := p * h.total
+	var cumulative float64
+	for i, weight := range h.buckets {
+		cumulative += weight
+		if cumulative >= target {
+			return h.min + float64(i+1)*h.width
+		}
+	}
+	return h.max
+}
+
+// buildSeriesPredictions groups daily cost totals by (project, service),
+// fits a decaying-histogram percentile predictor to each series' history
+// (all but the most recent day), and reports the resulting MAE over that
+// same history.
+func buildSeriesPredictions(byDay map[dailyKey]float64, cfg anomalyDetectorConfig, now time.Time) map[string]*seriesPrediction {
+	series := make(map[string]*seriesPrediction)
+	points := make(map[string][]seriesPoint)
+
+	for key, cost := range byDay {
+		seriesKey := key.project + "/" + key.service
+		points[seriesKey] = append(points[seriesKey], seriesPoint{date: key.date, cost: cost})
+		if _, ok := series[seriesKey]; !ok {
+			series[seriesKey] = &seriesPrediction{projectID: key.project, serviceName: key.service}
+		}
+	}
+
+	for seriesKey, pts := range points {
+		sort.Slice(pts, func(i, j int) bool { return pts[i].date.Before(pts[j].date) })
+		series[seriesKey].history = pts
+
+		// Require at least 7 historical points plus the most recent day,
+		// matching the original z-score guardrail.
+		if len(pts) < 8 {
+			continue
+		}
+
+		historical := pts[:len(pts)-1]
+
+		min, max := historical[0].cost, historical[0].cost
+		for _, p := range historical {
+			if p.cost < min {
+				min = p.cost
+			}
+			if p.cost > max {
+				max = p.cost
+			}
+		}
+
+		hist := newDecayingHistogram(min, max, cfg.Buckets)
+		for _, p := range historical {
+			ageInDays := now.Sub(p.date).Hours() / 24
+			weight := math.Exp(-cfg.Lambda * ageInDays)
+			hist.add(p.cost, weight)
+		}
+
+		predicted := hist.percentile(cfg.Percentile) * cfg.Margin
+		series[seriesKey].predicted = predicted
+
+		var sumAbsErr float64
+		for _, p := range historical {
+			sumAbsErr += math.Abs(p.cost - predicted)
+		}
+		series[seriesKey].mae = sumAbsErr / float64(len(historical))
+	}
+
+	return series
+}
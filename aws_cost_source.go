@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/s3"
+	"github.com/xitongsys/parquet-go-source/s3v2"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+// awsCURRow mirrors the subset of AWS Cost & Usage Report columns (Parquet,
+// "resources and tags" export) that the analytics service needs.
+type awsCURRow struct {
+	LineItemUsageAccountID string  `parquet:"name=line_item_usage_account_id"`
+	ProductProductName     string  `parquet:"name=product_product_name"`
+	LineItemUsageStartDate string  `parquet:"name=line_item_usage_start_date"`
+	LineItemUnblendedCost  float64 `parquet:"name=line_item_unblended_cost"`
+	LineItemCreditCost     float64 `parquet:"name=line_item_credit_cost"`
+	LineItemCurrencyCode   string  `parquet:"name=line_item_currency_code"`
+	ProductRegion          string  `parquet:"name=product_region"`
+}
+
+// AWSCostSource ingests AWS Cost & Usage Reports delivered as Parquet files
+// to S3, one CUR manifest/partition per billing period.
+type AWSCostSource struct {
+	s3Client *s3.Client
+	bucket   string
+	prefix   string
+	currency *currencyConverter
+}
+
+// NewAWSCostSource configures an adapter for CUR exports under s3://bucket/prefix.
+func NewAWSCostSource(cfg aws.Config, bucket, prefix string, currency *currencyConverter) *AWSCostSource {
+	return &AWSCostSource{
+		s3Client: s3.NewFromConfig(cfg),
+		bucket:   bucket,
+		prefix:   prefix,
+		currency: currency,
+	}
+}
+
+func (s *AWSCostSource) Name() string { return "aws" }
+
+func (s *AWSCostSource) Fetch(ctx context.Context, window CostWindow) ([]CostData, error) {
+	keys, err := s.curObjectKeys(ctx, window)
+	if err != nil {
+		return nil, fmt.Errorf("aws source: failed to list CUR objects: %v", err)
+	}
+
+	var records []CostData
+	for _, key := range keys {
+		rows, err := s.readParquet(ctx, key, window)
+		if err != nil {
+			return nil, fmt.Errorf("aws source: failed to read %s: %v", key, err)
+		}
+		records = append(records, rows...)
+	}
+
+	return records, nil
+}
+
+// curObjectKeys lists the CUR Parquet partitions under the configured prefix
+// that fall within the requested window. CUR exports partition each billing
+// period under a "YYYYMMDD-YYYYMMDD" directory (first day of the month to
+// first day of the next), so object keys whose billing-period directory
+// doesn't overlap the window are skipped without being downloaded; every
+// object under the prefix is still listed. Keys where the billing period can't be parsed are kept and left
+// to readParquet's per-row date filter.
+func (s *AWSCostSource) curObjectKeys(ctx context.Context, window CostWindow) ([]string, error) {
+	var keys []string
+	paginator := s3.NewListObjectsV2Paginator(s.s3Client, &s3.ListObjectsV2Input{
+		Bucket: aws.String(s.bucket),
+		Prefix: aws.String(s.prefix),
+	})
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, obj := range page.Contents {
+			if obj.Key == nil || !isParquetObject(*obj.Key) {
+				continue
+			}
+			if billingPeriodStart, billingPeriodEnd, ok := parseBillingPeriod(*obj.Key); ok {
+				if !billingPeriodEnd.After(window.Start) || !billingPeriodStart.Before(window.End) {
+					continue
+				}
+			}
+			keys = append(keys, *obj.Key)
+		}
+	}
+	return keys, nil
+}
+
+func isParquetObject(key string) bool {
+	return len(key) > 8 && key[len(key)-8:] == ".parquet"
+}
+
+// parseBillingPeriod extracts the "YYYYMMDD-YYYYMMDD" billing-period
+// directory CUR exports embed in every report key, returning ok=false if no
+// path segment matches.
+func parseBillingPeriod(key string) (start, end time.Time, ok bool) {
+	for _, segment := range strings.Split(key, "/") {
+		start, end, ok = parseBillingPeriodSegment(segment)
+		if ok {
+			return start, end, true
+		}
+	}
+	return time.Time{}, time.Time{}, false
+}
+
+func parseBillingPeriodSegment(segment string) (start, end time.Time, ok bool) {
+	parts := strings.SplitN(segment, "-", 2)
+	if len(parts) != 2 {
+		return time.Time{}, time.Time{}, false
+	}
+	start, err := time.Parse("20060102", parts[0])
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	end, err = time.Parse("20060102", parts[1])
+	if err != nil {
+		return time.Time{}, time.Time{}, false
+	}
+	return start, end, true
+}
+
+// parseCURDate parses the CUR "line_item_usage_start_date" column, which AWS
+// delivers as an ISO-8601 timestamp (e.g. "2024-01-01T00:00:00Z").
+func parseCURDate(value string) (time.Time, error) {
+	return time.Parse(time.RFC3339, value)
+}
+
+func (s *AWSCostSource) readParquet(ctx context.Context, key string, window CostWindow) ([]CostData, error) {
+	fr, err := s3v2.NewS3FileReaderWithClient(ctx, s.s3Client, s.bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	defer fr.Close()
+
+	pr, err := reader.NewParquetReader(fr, new(awsCURRow), 4)
+	if err != nil {
+		return nil, err
+	}
+	defer pr.ReadStop()
+
+	num := int(pr.GetNumRows())
+	rows := make([]awsCURRow, num)
+	if err := pr.Read(&rows); err != nil {
+		return nil, err
+	}
+
+	records := make([]CostData, 0, num)
+	for _, row := range rows {
+		usageDate, err := parseCURDate(row.LineItemUsageStartDate)
+		if err != nil {
+			continue
+		}
+		if usageDate.Before(window.Start) || !usageDate.Before(window.End) {
+			continue
+		}
+		cost := row.LineItemUnblendedCost
+		credits := row.LineItemCreditCost
+		if s.currency != nil {
+			cost = s.currency.convert(cost, row.LineItemCurrencyCode)
+			credits = s.currency.convert(credits, row.LineItemCurrencyCode)
+		}
+		records = append(records, CostData{
+			ProjectID:   row.LineItemUsageAccountID,
+			ServiceName: row.ProductProductName,
+			UsageDate:   usageDate,
+			Cost:        cost,
+			Credits:     credits,
+			Currency:    row.LineItemCurrencyCode,
+			Location:    row.ProductRegion,
+			Provider:    ProviderAWS,
+		})
+	}
+
+	return records, nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+)
+
+// AzureCostSource ingests Azure Cost Management Exports, which Azure
+// delivers as daily CSV blobs (one per export run) to a storage container.
+type AzureCostSource struct {
+	blobClient    *azblob.Client
+	containerName string
+	prefix        string
+	currency      *currencyConverter
+}
+
+// NewAzureCostSource configures an adapter for cost exports under the given
+// container/prefix.
+func NewAzureCostSource(blobClient *azblob.Client, containerName, prefix string, currency *currencyConverter) *AzureCostSource {
+	return &AzureCostSource{
+		blobClient:    blobClient,
+		containerName: containerName,
+		prefix:        prefix,
+		currency:      currency,
+	}
+}
+
+func (s *AzureCostSource) Name() string { return "azure" }
+
+func (s *AzureCostSource) Fetch(ctx context.Context, window CostWindow) ([]CostData, error) {
+	pager := s.blobClient.NewListBlobsFlatPager(s.containerName, &azblob.ListBlobsFlatOptions{
+		Prefix: &s.prefix,
+	})
+
+	var records []CostData
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("azure source: failed to list export blobs: %v", err)
+		}
+		for _, blob := range page.Segment.BlobItems {
+			if blob.Name == nil {
+				continue
+			}
+			if exportDate, ok := parseExportBlobDate(*blob.Name); ok && exportDate.Before(window.Start.AddDate(0, 0, -1)) {
+				// Exports run daily and cover the prior day's usage, so a
+				// blob dated well before the window can't contribute rows.
+				continue
+			}
+			rows, err := s.readExportCSV(ctx, *blob.Name, window)
+			if err != nil {
+				return nil, fmt.Errorf("azure source: failed to read %s: %v", *blob.Name, err)
+			}
+			records = append(records, rows...)
+		}
+	}
+
+	return records, nil
+}
+
+// exportCSVColumns indexes the columns of the Azure Cost Management Export
+// "ActualCost" CSV schema that the analytics service needs.
+type exportCSVColumns struct {
+	date, resourceGroup, meterCategory, cost, creditAmount, currency, region int
+}
+
+// parseExportBlobDate extracts a "YYYYMMDD" run-date segment from an export
+// blob's path, if present, so listing can skip blobs that predate the
+// requested window without downloading and parsing them.
+func parseExportBlobDate(blobName string) (time.Time, bool) {
+	for _, segment := range strings.Split(blobName, "/") {
+		if len(segment) >= 8 {
+			if t, err := time.Parse("20060102", segment[:8]); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func (s *AzureCostSource) readExportCSV(ctx context.Context, blobName string, window CostWindow) ([]CostData, error) {
+	resp, err := s.blobClient.DownloadStream(ctx, s.containerName, blobName, nil)
+	if err != nil {
+		return nil, err
+	}
+	body := resp.Body
+	defer body.Close()
+
+	r := csv.NewReader(body)
+	header, err := r.Read()
+	if err != nil {
+		if err == io.EOF {
+			return nil, nil
+		}
+		return nil, err
+	}
+	cols, err := indexExportColumns(header)
+	if err != nil {
+		return nil, err
+	}
+
+	var records []CostData
+	for {
+		row, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		usageDate, err := time.Parse("2006-01-02", row[cols.date])
+		if err != nil {
+			continue
+		}
+		if usageDate.Before(window.Start) || !usageDate.Before(window.End) {
+			continue
+		}
+		cost, _ := strconv.ParseFloat(row[cols.cost], 64)
+		credits, _ := strconv.ParseFloat(row[cols.creditAmount], 64)
+		currencyCode := row[cols.currency]
+
+		if s.currency != nil {
+			cost = s.currency.convert(cost, currencyCode)
+			credits = s.currency.convert(credits, currencyCode)
+		}
+
+		records = append(records, CostData{
+			ProjectID:   row[cols.resourceGroup],
+			ServiceName: row[cols.meterCategory],
+			UsageDate:   usageDate,
+			Cost:        cost,
+			Credits:     credits,
+			Currency:    currencyCode,
+			Location:    row[cols.region],
+			Provider:    ProviderAzure,
+		})
+	}
+
+	return records, nil
+}
+
+func indexExportColumns(header []string) (exportCSVColumns, error) {
+	index := make(map[string]int, len(header))
+	for i, name := range header {
+		index[name] = i
+	}
+
+	required := []string{
+		"Date", "ResourceGroup", "MeterCategory", "CostInBillingCurrency",
+		"CreditAmount", "BillingCurrency", "ResourceLocation",
+	}
+	for _, name := range required {
+		if _, ok := index[name]; !ok {
+			return exportCSVColumns{}, fmt.Errorf("export CSV missing expected column %q", name)
+		}
+	}
+
+	return exportCSVColumns{
+		date:          index["Date"],
+		resourceGroup: index["ResourceGroup"],
+		meterCategory: index["MeterCategory"],
+		cost:          index["CostInBillingCurrency"],
+		creditAmount:  index["CreditAmount"],
+		currency:      index["BillingCurrency"],
+		region:        index["ResourceLocation"],
+	}, nil
+}
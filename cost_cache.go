@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"sync"
+	"time"
+)
+
+// cacheEntry holds a cached handler response alongside when it was computed,
+// so handlers can surface staleness to clients.
+type cacheEntry struct {
+	value      interface{}
+	computedAt time.Time
+}
+
+// resultCache is a process-local cache for the trends/anomalies/forecast
+// endpoints, keyed by endpoint name and request params. It is populated by a
+// background refresh loop on a fixed interval; handlers read it directly and
+// only fall back to on-demand recomputation on a miss.
+type resultCache struct {
+	mu      sync.RWMutex
+	entries map[string]cacheEntry
+}
+
+func newResultCache() *resultCache {
+	return &resultCache{entries: make(map[string]cacheEntry)}
+}
+
+func cacheKey(endpoint string, params ...interface{}) string {
+	return fmt.Sprintf("%s:%v", endpoint, params)
+}
+
+func (c *resultCache) get(key string) (cacheEntry, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	entry, ok := c.entries[key]
+	return entry, ok
+}
+
+func (c *resultCache) set(key string, value interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = cacheEntry{value: value, computedAt: time.Now()}
+}
+
+// cachedTrends is the JSON envelope returned by /api/trends, carrying the
+// cache timestamp so clients can display staleness.
+type cachedTrends struct {
+	Trends     []CostTrend `json:"trends"`
+	ComputedAt time.Time   `json:"computed_at"`
+}
+
+type cachedAnomalies struct {
+	Anomalies  []Anomaly `json:"anomalies"`
+	ComputedAt time.Time `json:"computed_at"`
+}
+
+type cachedForecast struct {
+	Forecast   []ForecastPoint `json:"forecast"`
+	ComputedAt time.Time       `json:"computed_at"`
+}
+
+// defaultCacheRefreshInterval is how often the background loop recomputes
+// the cached trends/anomalies/forecast for the default request params.
+const defaultCacheRefreshInterval = time.Hour
+
+// defaultCachedDays/defaultCachedThreshold/defaultCachedForecastDays are the
+// params the background refresh loop warms; handlers serving any other
+// params fall back to on-demand computation. defaultCachedThreshold is the
+// anomaly margin: how far above the predicted P95 a day's cost must land
+// before it's flagged (see anomaly_detector.go).
+const (
+	defaultCachedDays         = 30
+	defaultCachedThreshold    = 1.2
+	defaultCachedForecastDays = 7
+)
+
+// startCacheRefreshLoop populates ca.cache on the given interval for the
+// lifetime of the process. Handlers fall back to on-demand recomputation
+// until the first refresh completes or for any params the loop doesn't warm.
+func (ca *CostAnalytics) startCacheRefreshLoop(interval time.Duration) {
+	go func() {
+		ca.refreshCache()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			ca.refreshCache()
+		}
+	}()
+}
+
+func (ca *CostAnalytics) refreshCache() {
+	if trends, err := ca.GetCostTrends(defaultCachedDays); err == nil {
+		ca.cache.set(cacheKey("trends", defaultCachedDays), cachedTrends{Trends: trends, ComputedAt: time.Now()})
+	} else {
+		log.Printf("cache refresh: trends: %v", err)
+	}
+
+	if anomalies, err := ca.DetectAnomalies(defaultCachedDays, defaultCachedThreshold); err == nil {
+		ca.cache.set(cacheKey("anomalies", defaultCachedDays, defaultCachedThreshold), cachedAnomalies{Anomalies: anomalies, ComputedAt: time.Now()})
+	} else {
+		log.Printf("cache refresh: anomalies: %v", err)
+	}
+
+	if forecast, err := ca.ForecastCosts(defaultCachedForecastDays); err == nil {
+		ca.cache.set(cacheKey("forecast", defaultCachedForecastDays), cachedForecast{Forecast: forecast, ComputedAt: time.Now()})
+	} else {
+		log.Printf("cache refresh: forecast: %v", err)
+	}
+}
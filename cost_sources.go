@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"time"
+)
+
+// CostWindow bounds a Fetch call to a half-open date range [Start, End).
+type CostWindow struct {
+	Start time.Time
+	End   time.Time
+}
+
+// CostSource is implemented by each cloud provider's billing adapter. Fetch
+// normalizes that provider's native export format into CostData records so
+// GetCostTrends, DetectAnomalies, and ForecastCosts can operate on a single
+// merged stream instead of provider-specific SQL.
+type CostSource interface {
+	// Name identifies the source in logs and error messages.
+	Name() string
+	// Fetch returns normalized cost records for the given window.
+	Fetch(ctx context.Context, window CostWindow) ([]CostData, error)
+}
+
+// currencyConverter converts a cost expressed in `from` currency into the
+// analytics service's reporting currency. Adapters hold one of these so GCP,
+// AWS, and Azure costs can be merged and compared directly.
+type currencyConverter struct {
+	reportingCurrency string
+	rates             map[string]float64 // units of reportingCurrency per 1 unit of the source currency
+}
+
+func newCurrencyConverter(reportingCurrency string, rates map[string]float64) *currencyConverter {
+	if rates == nil {
+		rates = map[string]float64{}
+	}
+	return &currencyConverter{reportingCurrency: reportingCurrency, rates: rates}
+}
+
+func (c *currencyConverter) convert(amount float64, from string) float64 {
+	if from == "" || from == c.reportingCurrency {
+		return amount
+	}
+	rate, ok := c.rates[from]
+	if !ok {
+		// No configured rate: assume parity rather than silently dropping cost.
+		return amount
+	}
+	return amount * rate
+}
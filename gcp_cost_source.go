@@ -0,0 +1,94 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"cloud.google.com/go/bigquery"
+	"google.golang.org/api/iterator"
+)
+
+// GCPCostSource reads normalized billing rows out of a BigQuery billing
+// export table (the same table the original GCP-only implementation queried
+// directly from GetCostTrends/DetectAnomalies).
+type GCPCostSource struct {
+	client    *bigquery.Client
+	projectID string
+	datasetID string
+	tableID   string
+	currency  *currencyConverter
+
+	// onBytesScanned, if set, is called with each query's TotalBytesProcessed
+	// so callers can feed a cloudcost_bigquery_bytes_scanned_total counter.
+	onBytesScanned func(int64)
+}
+
+// NewGCPCostSource wires a BigQuery client to a specific project/dataset/table.
+func NewGCPCostSource(client *bigquery.Client, projectID, datasetID, tableID string, currency *currencyConverter) *GCPCostSource {
+	return &GCPCostSource{
+		client:    client,
+		projectID: projectID,
+		datasetID: datasetID,
+		tableID:   tableID,
+		currency:  currency,
+	}
+}
+
+func (s *GCPCostSource) Name() string { return "gcp" }
+
+func (s *GCPCostSource) Fetch(ctx context.Context, window CostWindow) ([]CostData, error) {
+	query := fmt.Sprintf(`
+		SELECT
+			project.id as project_id,
+			service.description as service_description,
+			sku.description as sku_description,
+			TIMESTAMP(DATE(usage_start_time)) as usage_start_time,
+			cost,
+			IFNULL((SELECT SUM(amount) FROM UNNEST(credits)), 0) as credits,
+			currency,
+			IFNULL(location.location, "") as location_location
+		FROM %s.%s.%s
+		WHERE DATE(usage_start_time) >= @start AND DATE(usage_start_time) < @end
+	`, s.projectID, s.datasetID, s.tableID)
+
+	q := s.client.Query(query)
+	q.Parameters = []bigquery.QueryParameter{
+		{Name: "start", Value: window.Start},
+		{Name: "end", Value: window.End},
+	}
+
+	var it *bigquery.RowIterator
+	err := retryWithBackoff(ctx, defaultRetryConfig, func() error {
+		var readErr error
+		it, readErr = q.Read(ctx)
+		return readErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("gcp source: failed to execute query: %v", err)
+	}
+	if s.onBytesScanned != nil {
+		s.onBytesScanned(it.TotalBytesProcessed)
+	}
+
+	var records []CostData
+	for {
+		var row CostData
+		err := retryWithBackoff(ctx, defaultRetryConfig, func() error {
+			return it.Next(&row)
+		})
+		if err != nil {
+			if err == iterator.Done {
+				break
+			}
+			return nil, fmt.Errorf("gcp source: failed to read row: %v", err)
+		}
+		row.Provider = ProviderGCP
+		if s.currency != nil {
+			row.Cost = s.currency.convert(row.Cost, row.Currency)
+			row.Credits = s.currency.convert(row.Credits, row.Currency)
+		}
+		records = append(records, row)
+	}
+
+	return records, nil
+}
@@ -0,0 +1,209 @@
+package main
+
+import "math"
+
+// seasonLength is the Holt-Winters seasonal period: 7 days captures the
+// weekday/weekend cycle in cloud usage.
+const seasonLength = 7
+
+// holtWintersParams are the triple exponential smoothing coefficients:
+// Alpha smooths the level, Beta the trend, Gamma the seasonal component.
+type holtWintersParams struct {
+	Alpha, Beta, Gamma float64
+}
+
+// holtWintersFit holds the fitted state needed to forecast forward from the
+// end of the training series, plus the residual stddev used for prediction
+// intervals.
+type holtWintersFit struct {
+	params         holtWintersParams
+	level          float64
+	trend          float64
+	seasonal       []float64 // length seasonLength, most recent period
+	residualStddev float64
+	additive       bool // true if the series required the additive fallback
+	trainLen       int  // number of points the fit was trained on, for phasing forecast's seasonal index
+}
+
+// fitHoltWinters fits multiplicative triple exponential smoothing to
+// `series` (oldest first), falling back to additive seasonality when any
+// value in the series is zero (multiplicative division would blow up). A
+// level that reaches zero during a multiplicative fit does not switch the
+// fit to additive; that step's level update just skips the seasonal
+// division. alpha/beta/gamma are chosen by gridSearchHoltWinters minimizing
+// SSE on a held-out tail.
+func fitHoltWinters(series []float64, params holtWintersParams) holtWintersFit {
+	n := len(series)
+	additive := seriesHasZero(series)
+
+	// Initialize level as the mean of the first period and trend as the
+	// mean period-over-period difference, per the standard HW initialization.
+	level := mean(series[:seasonLength])
+	var trend float64
+	if n >= 2*seasonLength {
+		trend = (mean(series[seasonLength:2*seasonLength]) - level) / seasonLength
+	}
+
+	seasonal := make([]float64, seasonLength)
+	for i := 0; i < seasonLength; i++ {
+		if additive {
+			seasonal[i] = series[i] - level
+		} else if level != 0 {
+			seasonal[i] = series[i] / level
+		} else {
+			seasonal[i] = 0
+		}
+	}
+
+	var residuals []float64
+	for t := seasonLength; t < n; t++ {
+		seasonIdx := t % seasonLength
+		prevLevel := level
+		prevTrend := trend
+		prevSeasonal := seasonal[seasonIdx]
+
+		var forecast float64
+		if additive {
+			forecast = prevLevel + prevTrend + prevSeasonal
+		} else {
+			forecast = (prevLevel + prevTrend) * prevSeasonal
+		}
+		residuals = append(residuals, series[t]-forecast)
+
+		if additive {
+			level = params.Alpha*(series[t]-prevSeasonal) + (1-params.Alpha)*(prevLevel+prevTrend)
+		} else if prevLevel != 0 {
+			level = params.Alpha*(series[t]/prevSeasonal) + (1-params.Alpha)*(prevLevel+prevTrend)
+		} else {
+			level = params.Alpha*series[t] + (1-params.Alpha)*(prevLevel+prevTrend)
+		}
+
+		trend = params.Beta*(level-prevLevel) + (1-params.Beta)*prevTrend
+
+		if additive {
+			seasonal[seasonIdx] = params.Gamma*(series[t]-level) + (1-params.Gamma)*prevSeasonal
+		} else if level != 0 {
+			seasonal[seasonIdx] = params.Gamma*(series[t]/level) + (1-params.Gamma)*prevSeasonal
+		} else {
+			seasonal[seasonIdx] = prevSeasonal
+		}
+	}
+
+	return holtWintersFit{
+		params:         params,
+		level:          level,
+		trend:          trend,
+		seasonal:       seasonal,
+		residualStddev: stddev(residuals),
+		additive:       additive,
+		trainLen:       n,
+	}
+}
+
+// forecast predicts h steps past the end of the training series:
+// (level + h*trend) * seasonal[...] for multiplicative, or the additive
+// equivalent. The seasonal slice is indexed by absolute phase (the same
+// `t % seasonLength` used while fitting), so the forecast target at
+// absolute index trainLen-1+h uses seasonIdx = (trainLen-1+h) % seasonLength
+// rather than (h-1) % seasonLength, which is only correct when trainLen is
+// itself a multiple of seasonLength.
+func (f holtWintersFit) forecast(h int) float64 {
+	seasonIdx := (f.trainLen - 1 + h) % seasonLength
+	s := f.seasonal[seasonIdx]
+	if f.additive {
+		return f.level + float64(h)*f.trend + s
+	}
+	return (f.level + float64(h)*f.trend) * s
+}
+
+// predictionInterval returns the +/- half-width of a ~95% prediction
+// interval around a forecast at horizon h, widening with sqrt(h) to reflect
+// compounding uncertainty further into the future.
+func (f holtWintersFit) predictionInterval(h int) float64 {
+	return 1.96 * f.residualStddev * math.Sqrt(float64(h))
+}
+
+// holtWintersGrid is the alpha/beta/gamma grid searched to minimize SSE on
+// the held-out tail. Coarse but adequate given the forecast horizon is a
+// handful of days.
+var holtWintersGrid = []float64{0.1, 0.3, 0.5, 0.7, 0.9}
+
+// gridSearchHoltWinters fits Holt-Winters for every (alpha, beta, gamma)
+// combination in holtWintersGrid, holding out the last `holdout` points to
+// score each fit by SSE, and returns the best-fitting parameters refit on
+// the full series.
+func gridSearchHoltWinters(series []float64, holdout int) holtWintersFit {
+	if holdout < 1 {
+		holdout = 1
+	}
+	if len(series)-holdout < 2*seasonLength {
+		holdout = len(series) - 2*seasonLength
+	}
+	if holdout < 1 {
+		holdout = 1
+	}
+
+	train := series[:len(series)-holdout]
+	actualTail := series[len(series)-holdout:]
+
+	var best holtWintersFit
+	bestSSE := math.Inf(1)
+
+	for _, alpha := range holtWintersGrid {
+		for _, beta := range holtWintersGrid {
+			for _, gamma := range holtWintersGrid {
+				params := holtWintersParams{Alpha: alpha, Beta: beta, Gamma: gamma}
+				fit := fitHoltWinters(train, params)
+
+				var sse float64
+				for i, actual := range actualTail {
+					predicted := fit.forecast(i + 1)
+					diff := actual - predicted
+					sse += diff * diff
+				}
+
+				if sse < bestSSE {
+					bestSSE = sse
+					best = fit
+				}
+			}
+		}
+	}
+
+	// Refit the winning params on the full series so forecasting starts
+	// from the most recent observation, not the held-out training cutoff.
+	return fitHoltWinters(series, best.params)
+}
+
+func seriesHasZero(series []float64) bool {
+	for _, v := range series {
+		if v == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	var sum float64
+	for _, v := range values {
+		sum += v
+	}
+	return sum / float64(len(values))
+}
+
+func stddev(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	m := mean(values)
+	var sumSq float64
+	for _, v := range values {
+		diff := v - m
+		sumSq += diff * diff
+	}
+	return math.Sqrt(sumSq / float64(len(values)))
+}
@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricsCollector owns the Prometheus gauges/histograms this service
+// exposes at /metrics. Values are populated by a periodic refresh that runs
+// on the result cache's interval and calls the same fetch/DetectAnomalies/
+// ForecastCosts paths as the JSON handlers; it does not read the result
+// cache itself. Scraping /metrics never triggers its own BigQuery/S3/Blob
+// reads.
+type metricsCollector struct {
+	dailyCost           *prometheus.GaugeVec
+	anomalyDeviationPct *prometheus.GaugeVec
+	forecastNext7d      prometheus.Gauge
+	queryDuration       *prometheus.HistogramVec
+	bytesScanned        prometheus.Counter
+	buildInfo           *prometheus.GaugeVec
+}
+
+func newMetricsCollector(version string) *metricsCollector {
+	c := &metricsCollector{
+		dailyCost: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cloudcost_daily_total",
+			Help: "Most recent daily cost total per project/service/currency.",
+		}, []string{"project", "service", "currency"}),
+
+		anomalyDeviationPct: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cloudcost_anomaly_zscore",
+			Help: "Percentage deviation of the most recent detected anomaly per project/service (legacy name, now percentile-based).",
+		}, []string{"project", "service"}),
+
+		forecastNext7d: promauto.NewGauge(prometheus.GaugeOpts{
+			Name: "cloudcost_forecast_next7d",
+			Help: "Forecasted total cost for the next 7 days, summed across all projects (ForecastCosts does not break the forecast out per project).",
+		}),
+
+		queryDuration: promauto.NewHistogramVec(prometheus.HistogramOpts{
+			Name:    "cloudcost_query_duration_seconds",
+			Help:    "Duration of cost-data fetches (across all configured sources) and anomaly detection runs made while refreshing metrics.",
+			Buckets: prometheus.DefBuckets,
+		}, []string{"operation"}),
+
+		bytesScanned: promauto.NewCounter(prometheus.CounterOpts{
+			Name: "cloudcost_bigquery_bytes_scanned_total",
+			Help: "Cumulative bytes scanned by BigQuery queries issued by this service.",
+		}),
+
+		buildInfo: promauto.NewGaugeVec(prometheus.GaugeOpts{
+			Name: "cloudcost_build_info",
+			Help: "Build metadata; always 1, labeled with the running version.",
+		}, []string{"version"}),
+	}
+
+	c.buildInfo.WithLabelValues(version).Set(1)
+
+	return c
+}
+
+// observeQueryDuration records how long a metrics refresh step took, labeled
+// by a short operation name ("fetch_cost_data" or "detect_anomalies").
+func (c *metricsCollector) observeQueryDuration(operation string, d time.Duration) {
+	c.queryDuration.WithLabelValues(operation).Observe(d.Seconds())
+}
+
+// dailyCostKey groups the most recent day's merged cost records by the
+// label set the cloudcost_daily_total gauge exposes.
+type dailyCostKey struct {
+	project, service, currency string
+}
+
+// refresh recomputes every gauge from the latest raw cost records,
+// anomalies, and forecast. It reuses the same CostSource/DetectAnomalies/
+// ForecastCosts paths the JSON handlers use, so /metrics never needs its
+// own query logic.
+func (ca *CostAnalytics) refreshMetrics() {
+	if ca.metrics == nil {
+		return
+	}
+
+	ctx := context.Background()
+	now := time.Now().UTC().Truncate(24 * time.Hour)
+	window := CostWindow{Start: now.AddDate(0, 0, -1), End: now}
+
+	start := time.Now()
+	records, err := ca.fetchMerged(ctx, window)
+	ca.metrics.observeQueryDuration("fetch_cost_data", time.Since(start))
+	if err == nil {
+		totals := make(map[dailyCostKey]float64)
+		for _, r := range records {
+			totals[dailyCostKey{r.ProjectID, r.ServiceName, ca.reportingCurrency}] += r.Cost + r.Credits
+		}
+		// Reset before repopulating so a project/service that stopped
+		// incurring cost drops out of the series instead of reporting its
+		// last value forever.
+		ca.metrics.dailyCost.Reset()
+		for key, cost := range totals {
+			ca.metrics.dailyCost.WithLabelValues(key.project, key.service, key.currency).Set(cost)
+		}
+	}
+
+	startAnomalies := time.Now()
+	anomalies, err := ca.DetectAnomalies(defaultCachedDays, defaultCachedThreshold)
+	ca.metrics.observeQueryDuration("detect_anomalies", time.Since(startAnomalies))
+	if err == nil {
+		// Reset so a resolved anomaly stops reporting and doesn't keep
+		// tripping alerts built on this gauge.
+		ca.metrics.anomalyDeviationPct.Reset()
+		for _, anomaly := range anomalies {
+			ca.metrics.anomalyDeviationPct.WithLabelValues(anomaly.ProjectID, anomaly.ServiceName).Set(anomaly.DeviationPct)
+		}
+	}
+
+	forecast, err := ca.ForecastCosts(defaultCachedForecastDays)
+	if err == nil {
+		var next7d float64
+		for _, point := range forecast {
+			next7d += point.TotalCost
+		}
+		ca.metrics.forecastNext7d.Set(next7d)
+	}
+}
+
+// startMetricsRefreshLoop keeps the Prometheus gauges warm on the same
+// cadence as the result cache.
+func (ca *CostAnalytics) startMetricsRefreshLoop(interval time.Duration) {
+	go func() {
+		ca.refreshMetrics()
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			ca.refreshMetrics()
+		}
+	}()
+}
+
+func metricsHandler() http.Handler {
+	return promhttp.Handler()
+}
@@ -0,0 +1,229 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+// NotificationRule routes anomalies at or above MinSeverity for a given project
+// (or every project, if ProjectID is empty) to a named Notifier, except
+// during its configured quiet hours.
+type NotificationRule struct {
+	ID          string `json:"id"`
+	ProjectID   string `json:"project_id,omitempty"` // empty matches all projects
+	MinSeverity string `json:"min_severity"`         // "Low", "Medium", or "High"
+	Notifier    string `json:"notifier"`             // name registered in the notifier registry
+	QuietStart  int    `json:"quiet_hours_start"`    // hour-of-day, 0-23, UTC; QuietStart==QuietEnd disables quiet hours
+	QuietEnd    int    `json:"quiet_hours_end"`      // hour-of-day, 0-23, UTC
+}
+
+var severityRank = map[string]int{"Low": 0, "Medium": 1, "High": 2}
+
+func (rule NotificationRule) matches(anomaly Anomaly) bool {
+	if rule.ProjectID != "" && rule.ProjectID != anomaly.ProjectID {
+		return false
+	}
+	return severityRank[anomaly.Severity] >= severityRank[rule.MinSeverity]
+}
+
+func (rule NotificationRule) inQuietHours(at time.Time) bool {
+	if rule.QuietStart == rule.QuietEnd {
+		return false
+	}
+	hour := at.UTC().Hour()
+	if rule.QuietStart < rule.QuietEnd {
+		return hour >= rule.QuietStart && hour < rule.QuietEnd
+	}
+	// Wraps past midnight, e.g. 22 -> 6.
+	return hour >= rule.QuietStart || hour < rule.QuietEnd
+}
+
+// notificationRuleStore is an in-memory CRUD store for NotificationRules and
+// the named Notifier registry rules route to. A real deployment would back
+// this with a database, but the rule set is small and changes rarely enough
+// that in-memory plus a mutex matches the rest of the service's footprint.
+type notificationRuleStore struct {
+	mu         sync.RWMutex
+	rules      map[string]NotificationRule
+	notifiers  map[string]Notifier
+	nextID     int
+	dispatched map[string]bool // rule ID + anomalyDedupKey already sent, so re-detecting the same anomaly doesn't re-notify
+}
+
+func newNotificationRuleStore() *notificationRuleStore {
+	return &notificationRuleStore{
+		rules:      make(map[string]NotificationRule),
+		notifiers:  make(map[string]Notifier),
+		dispatched: make(map[string]bool),
+	}
+}
+
+func (s *notificationRuleStore) registerNotifier(name string, notifier Notifier) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.notifiers[name] = notifier
+}
+
+func (s *notificationRuleStore) create(rule NotificationRule) NotificationRule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextID++
+	rule.ID = fmt.Sprintf("rule-%d", s.nextID)
+	s.rules[rule.ID] = rule
+	return rule
+}
+
+func (s *notificationRuleStore) list() []NotificationRule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	rules := make([]NotificationRule, 0, len(s.rules))
+	for _, rule := range s.rules {
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+func (s *notificationRuleStore) update(id string, rule NotificationRule) (NotificationRule, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.rules[id]; !ok {
+		return NotificationRule{}, false
+	}
+	rule.ID = id
+	s.rules[id] = rule
+	return rule, true
+}
+
+func (s *notificationRuleStore) delete(id string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.rules[id]; !ok {
+		return false
+	}
+	delete(s.rules, id)
+	return true
+}
+
+// dispatch routes an anomaly to every rule that matches it and isn't in
+// quiet hours, logging (rather than failing) individual notifier errors so
+// one bad destination doesn't block the rest. Each (rule, anomaly) pair is
+// only ever dispatched once *successfully*: startAnomalyAlertLoop re-runs
+// DetectAnomalies on every tick and will keep re-flagging the same day's
+// anomaly, and unlike PagerDutyNotifier (which dedups server-side via
+// dedup_key), Slack and webhook destinations have no dedup of their own and
+// would otherwise be re-sent every tick indefinitely. A pair is only marked
+// dispatched once Notify returns nil, so a transient failure is retried on
+// the next tick instead of being silently dropped.
+func (s *notificationRuleStore) dispatch(ctx context.Context, anomaly Anomaly, at time.Time) {
+	dedupKey := anomalyDedupKey(anomaly)
+
+	s.mu.RLock()
+	rules := make([]NotificationRule, 0, len(s.rules))
+	for _, rule := range s.rules {
+		if !rule.matches(anomaly) || rule.inQuietHours(at) {
+			continue
+		}
+		if s.dispatched[rule.ID+"/"+dedupKey] {
+			continue
+		}
+		rules = append(rules, rule)
+	}
+	notifiers := s.notifiers
+	s.mu.RUnlock()
+
+	for _, rule := range rules {
+		notifier, ok := notifiers[rule.Notifier]
+		if !ok {
+			log.Printf("notification rule %s: unknown notifier %q", rule.ID, rule.Notifier)
+			continue
+		}
+		if err := notifier.Notify(ctx, anomaly); err != nil {
+			log.Printf("notification rule %s: failed to notify via %s: %v", rule.ID, rule.Notifier, err)
+			continue
+		}
+		s.mu.Lock()
+		s.dispatched[rule.ID+"/"+dedupKey] = true
+		s.mu.Unlock()
+	}
+}
+
+// defaultAlertCheckInterval is how often startAnomalyAlertLoop re-runs
+// DetectAnomalies and dispatches any new anomalies to notification rules.
+const defaultAlertCheckInterval = 15 * time.Minute
+
+// startAnomalyAlertLoop periodically re-runs anomaly detection and routes
+// each detected anomaly through the notification rule store, so alerting
+// happens independently of anyone polling the JSON API.
+func (ca *CostAnalytics) startAnomalyAlertLoop(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for range ticker.C {
+			anomalies, err := ca.DetectAnomalies(defaultCachedDays, defaultCachedThreshold)
+			if err != nil {
+				log.Printf("anomaly alert loop: %v", err)
+				continue
+			}
+			now := time.Now()
+			for _, anomaly := range anomalies {
+				ca.notificationRules.dispatch(context.Background(), anomaly, now)
+			}
+		}
+	}()
+}
+
+// HTTP handlers for notification rule CRUD.
+
+func (ca *CostAnalytics) handleListNotificationRules(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(ca.notificationRules.list())
+}
+
+func (ca *CostAnalytics) handleCreateNotificationRule(w http.ResponseWriter, r *http.Request) {
+	var rule NotificationRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	created := ca.notificationRules.create(rule)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(created)
+}
+
+func (ca *CostAnalytics) handleUpdateNotificationRule(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	var rule NotificationRule
+	if err := json.NewDecoder(r.Body).Decode(&rule); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	updated, ok := ca.notificationRules.update(id, rule)
+	if !ok {
+		http.Error(w, "notification rule not found", http.StatusNotFound)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(updated)
+}
+
+func (ca *CostAnalytics) handleDeleteNotificationRule(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	if !ca.notificationRules.delete(id) {
+		http.Error(w, "notification rule not found", http.StatusNotFound)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
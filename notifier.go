@@ -0,0 +1,210 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Notifier dispatches a detected anomaly to an external alerting destination.
+type Notifier interface {
+	// Notify sends the anomaly, returning an error if the destination
+	// rejected or could not be reached.
+	Notify(ctx context.Context, anomaly Anomaly) error
+}
+
+// PagerDutyNotifier sends anomalies to the PagerDuty Events API v2. Repeated
+// anomalies for the same (project, service, date) share a dedup key so they
+// coalesce into one incident instead of storming on-call.
+type PagerDutyNotifier struct {
+	routingKey string
+	httpClient *http.Client
+}
+
+// NewPagerDutyNotifier configures a notifier for the given Events API v2 routing key.
+func NewPagerDutyNotifier(routingKey string) *PagerDutyNotifier {
+	return &PagerDutyNotifier{routingKey: routingKey, httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+type pagerDutyEvent struct {
+	RoutingKey  string                `json:"routing_key"`
+	EventAction string                `json:"event_action"`
+	DedupKey    string                `json:"dedup_key"`
+	Payload     pagerDutyEventPayload `json:"payload"`
+}
+
+type pagerDutyEventPayload struct {
+	Summary   string `json:"summary"`
+	Source    string `json:"source"`
+	Severity  string `json:"severity"`
+	Timestamp string `json:"timestamp"`
+}
+
+func anomalyDedupKey(a Anomaly) string {
+	return fmt.Sprintf("%s/%s/%s", a.ProjectID, a.ServiceName, a.Date.Format("2006-01-02"))
+}
+
+func pagerDutySeverity(severity string) string {
+	switch severity {
+	case "High":
+		return "critical"
+	case "Medium":
+		return "warning"
+	default:
+		return "info"
+	}
+}
+
+func (n *PagerDutyNotifier) Notify(ctx context.Context, anomaly Anomaly) error {
+	event := pagerDutyEvent{
+		RoutingKey:  n.routingKey,
+		EventAction: "trigger",
+		DedupKey:    anomalyDedupKey(anomaly),
+		Payload: pagerDutyEventPayload{
+			Summary:   anomaly.Description,
+			Source:    "cloudcost-analytics",
+			Severity:  pagerDutySeverity(anomaly.Severity),
+			Timestamp: anomaly.Date.Format(time.RFC3339),
+		},
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("pagerduty: failed to encode event: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, "https://events.pagerduty.com/v2/enqueue", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("pagerduty: failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("pagerduty: request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("pagerduty: unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// SlackNotifier posts anomalies to a Slack incoming webhook as a formatted,
+// severity-colored attachment.
+type SlackNotifier struct {
+	webhookURL string
+	httpClient *http.Client
+}
+
+// NewSlackNotifier configures a notifier for the given incoming webhook URL.
+func NewSlackNotifier(webhookURL string) *SlackNotifier {
+	return &SlackNotifier{webhookURL: webhookURL, httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+type slackMessage struct {
+	Attachments []slackAttachment `json:"attachments"`
+}
+
+type slackAttachment struct {
+	Color string `json:"color"`
+	Title string `json:"title"`
+	Text  string `json:"text"`
+}
+
+func slackColor(severity string) string {
+	switch severity {
+	case "High":
+		return "#d32f2f"
+	case "Medium":
+		return "#f9a825"
+	default:
+		return "#388e3c"
+	}
+}
+
+func (n *SlackNotifier) Notify(ctx context.Context, anomaly Anomaly) error {
+	msg := slackMessage{
+		Attachments: []slackAttachment{{
+			Color: slackColor(anomaly.Severity),
+			Title: fmt.Sprintf("Cost anomaly: %s / %s", anomaly.ProjectID, anomaly.ServiceName),
+			Text:  anomaly.Description,
+		}},
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return fmt.Errorf("slack: failed to encode message: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.webhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("slack: failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("slack: request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("slack: unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+// WebhookNotifier posts the anomaly as JSON to an arbitrary HTTP endpoint,
+// signing the body with HMAC-SHA256 so the receiver can verify authenticity.
+type WebhookNotifier struct {
+	url        string
+	secret     string
+	httpClient *http.Client
+}
+
+// NewWebhookNotifier configures a generic HTTP webhook signed with secret.
+func NewWebhookNotifier(url, secret string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, secret: secret, httpClient: &http.Client{Timeout: 10 * time.Second}}
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, anomaly Anomaly) error {
+	body, err := json.Marshal(anomaly)
+	if err != nil {
+		return fmt.Errorf("webhook: failed to encode anomaly: %v", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("webhook: failed to build request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Signature-256", "sha256="+signHMAC(n.secret, body))
+
+	resp, err := n.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook: request failed: %v", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook: unexpected status %d", resp.StatusCode)
+	}
+
+	return nil
+}
+
+func signHMAC(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
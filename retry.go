@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"google.golang.org/api/googleapi"
+)
+
+// retryConfig tunes retryWithBackoff. Defaults match what the background
+// refresh loops need: a handful of attempts with jittered exponential
+// backoff, bounded so one blip can't stall a scheduled run indefinitely.
+type retryConfig struct {
+	MaxAttempts int
+	BaseDelay   time.Duration
+	MaxDelay    time.Duration
+}
+
+var defaultRetryConfig = retryConfig{
+	MaxAttempts: 5,
+	BaseDelay:   200 * time.Millisecond,
+	MaxDelay:    10 * time.Second,
+}
+
+// retryError wraps the last error from a retried operation with the number
+// of attempts made, so callers (and logs) can tell a persistent failure from
+// a one-off.
+type retryError struct {
+	attempts int
+	err      error
+}
+
+func (e *retryError) Error() string {
+	return fmt.Sprintf("failed after %d attempts: %v", e.attempts, e.err)
+}
+
+func (e *retryError) Unwrap() error { return e.err }
+
+// isTransientError classifies an error as worth retrying: network
+// timeouts/temporary errors, a mid-stream io.EOF, HTTP 5xx, and googleapi
+// 429 (rate limit, honoring Retry-After where present). Auth failures,
+// invalid queries, and other 4xx are treated as permanent.
+func isTransientError(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) {
+		if netErr.Timeout() {
+			return true
+		}
+		var temporary interface{ Temporary() bool }
+		if errors.As(err, &temporary) && temporary.Temporary() {
+			return true
+		}
+	}
+
+	var apiErr *googleapi.Error
+	if errors.As(err, &apiErr) {
+		if apiErr.Code == http.StatusTooManyRequests {
+			return true
+		}
+		if apiErr.Code >= 500 && apiErr.Code < 600 {
+			return true
+		}
+		return false
+	}
+
+	return false
+}
+
+// retryWithBackoff runs fn up to cfg.MaxAttempts times, retrying only
+// isTransientError failures with exponential backoff plus full jitter,
+// honoring ctx cancellation between attempts. A googleapi 429 carrying a
+// Retry-After header floors the delay at that value instead of the jittered
+// backoff, since the server has told us exactly how long to wait. Permanent
+// errors return immediately without consuming further attempts.
+func retryWithBackoff(ctx context.Context, cfg retryConfig, fn func() error) error {
+	var lastErr error
+
+	for attempt := 1; attempt <= cfg.MaxAttempts; attempt++ {
+		lastErr = fn()
+		if lastErr == nil {
+			return nil
+		}
+
+		if !isTransientError(lastErr) {
+			return lastErr
+		}
+
+		if attempt == cfg.MaxAttempts {
+			break
+		}
+
+		delay := backoffDelay(cfg, attempt)
+		if retryAfter, ok := retryAfterDelay(lastErr); ok && retryAfter > delay {
+			delay = retryAfter
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+
+	return &retryError{attempts: cfg.MaxAttempts, err: lastErr}
+}
+
+// backoffDelay computes exponential backoff with full jitter:
+// a random value in [0, min(MaxDelay, BaseDelay*2^(attempt-1))], inclusive.
+func backoffDelay(cfg retryConfig, attempt int) time.Duration {
+	exp := float64(cfg.BaseDelay) * math.Pow(2, float64(attempt-1))
+	capped := math.Min(exp, float64(cfg.MaxDelay))
+	return time.Duration(rand.Int63n(int64(capped) + 1))
+}
+
+// retryAfterDelay extracts a Retry-After delay from a googleapi.Error's
+// response headers, if present, supporting both the delta-seconds and
+// HTTP-date forms the header allows.
+func retryAfterDelay(err error) (time.Duration, bool) {
+	var apiErr *googleapi.Error
+	if !errors.As(err, &apiErr) || apiErr.Header == nil {
+		return 0, false
+	}
+
+	value := apiErr.Header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if when, err := http.ParseTime(value); err == nil {
+		if d := time.Until(when); d > 0 {
+			return d, true
+		}
+	}
+
+	return 0, false
+}